@@ -0,0 +1,148 @@
+package gopher_notify
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Feed 同步、强类型、零拷贝的多播通道
+// 与基于主题订阅的Broker不同，Feed以事件本身的类型作为身份标识：发布者调用Send时会阻塞，直至事件被当前全部订阅者接收完毕
+// 适用于进程内低延迟、带背压的事件分发场景；订阅者应当通过返回的Subscription调用Unsubscribe取消订阅，而不是自行关闭接收通道
+//
+// 泛型：
+//   - E 传递的事件数据类型
+type Feed[E any] struct {
+	// 保护下方字段的读写锁
+	lock sync.RWMutex
+	// 全部已订阅的接收通道
+	channels []chan<- E
+	// 每个订阅者对应的取消信号通道，与channels按下标一一对应
+	errChannels []chan error
+	// 复用的reflect.Select case表，仅在订阅者集合发生变化后的下一次Send时重建
+	cases []reflect.SelectCase
+	// 订阅者集合是否发生了变化，为true时下一次Send需要重建case表
+	dirty bool
+}
+
+// NewFeed 创建一个事件分发器
+//
+// 泛型：
+//   - E 传递的事件数据类型
+func NewFeed[E any]() *Feed[E] {
+	return &Feed[E]{}
+}
+
+// Subscription 表示一次通过Feed.Subscribe建立的订阅关系
+type Subscription struct {
+	// 取消订阅时实际执行的逻辑
+	unsubscribe func()
+	// 取消信号通道，取消订阅时会被关闭
+	err chan error
+}
+
+// Unsubscribe 取消这次订阅，对应的接收通道此后不会再收到新的事件
+func (sub Subscription) Unsubscribe() {
+	sub.unsubscribe()
+}
+
+// Err 返回取消信号通道，取消订阅时该通道会被关闭，可在select语句中监听取消事件
+func (sub Subscription) Err() <-chan error {
+	return sub.err
+}
+
+// Subscribe 使用一个接收通道订阅该Feed，此后每次Send都会向该通道投递事件
+// 由于Send是同步发送，ch应当有一定缓冲区或由专门的协程持续消费，否则会阻塞发布者
+//
+//   - ch 用于接收事件的通道
+func (feed *Feed[E]) Subscribe(ch chan<- E) Subscription {
+	feed.lock.Lock()
+	errChannel := make(chan error)
+	feed.channels = append(feed.channels, ch)
+	feed.errChannels = append(feed.errChannels, errChannel)
+	feed.dirty = true
+	feed.lock.Unlock()
+	return Subscription{
+		unsubscribe: func() {
+			feed.unsubscribe(ch, errChannel)
+		},
+		err: errChannel,
+	}
+}
+
+// 将指定的接收通道从订阅列表中移除，并关闭其对应的取消信号通道
+//
+//   - ch 要移除的接收通道
+//   - errChannel 该通道对应的取消信号通道
+func (feed *Feed[E]) unsubscribe(ch chan<- E, errChannel chan error) {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	for i, c := range feed.channels {
+		if c == ch {
+			feed.channels = append(feed.channels[:i], feed.channels[i+1:]...)
+			feed.errChannels = append(feed.errChannels[:i], feed.errChannels[i+1:]...)
+			feed.dirty = true
+			close(errChannel)
+			return
+		}
+	}
+}
+
+// 重建reflect.Select所需的case表，调用方需持有feed.lock的写锁
+// 每个订阅者对应两个相邻的case：偶数下标是向其接收通道发送事件的SelectSend case，奇数下标是监听其取消信号通道的SelectRecv case
+// 成对出现是为了让Send能够识别到“正在等待发送的订阅者于此刻被Unsubscribe”的情况：
+// 取消订阅时errChannel会被关闭，对应的Recv case会立即就绪，Send据此得知该订阅者已经放弃接收，从而转而移除这一对case，
+// 而不是永远阻塞在一个不会再有人读取的发送通道上
+func (feed *Feed[E]) rebuildCases() {
+	feed.cases = make([]reflect.SelectCase, 2*len(feed.channels))
+	for i, ch := range feed.channels {
+		feed.cases[2*i] = reflect.SelectCase{
+			Dir:  reflect.SelectSend,
+			Chan: reflect.ValueOf(ch),
+		}
+		feed.cases[2*i+1] = reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(feed.errChannels[i]),
+		}
+	}
+	feed.dirty = false
+}
+
+// Send 向当前全部订阅者同步广播一个事件，阻塞直至事件被每一个订阅者接收完毕
+// 若某个订阅者在Send等待其接收事件期间调用了Unsubscribe，Send会识别到该取消信号并跳过此次投递，而不会永远阻塞在它的接收通道上
+//
+//   - event 要广播的事件
+//
+// 返回投递到的订阅者数量（不包含发送过程中被取消订阅而跳过的订阅者）
+func (feed *Feed[E]) Send(event E) int {
+	// 订阅者集合发生过变化，重建case表
+	feed.lock.RLock()
+	dirty := feed.dirty
+	feed.lock.RUnlock()
+	if dirty {
+		feed.lock.Lock()
+		if feed.dirty {
+			feed.rebuildCases()
+		}
+		feed.lock.Unlock()
+	}
+	// 复制一份case表用于本次发送，避免发送过程中与订阅者变化相互影响
+	feed.lock.RLock()
+	cases := append([]reflect.SelectCase(nil), feed.cases...)
+	feed.lock.RUnlock()
+	value := reflect.ValueOf(event)
+	for i := 0; i < len(cases); i += 2 {
+		cases[i].Send = value
+	}
+	// 反复执行一次select：命中发送case视为投递成功，命中对应的取消case视为该订阅者已放弃接收，
+	// 两种情况都将这一对case从表中移除，直至全部订阅者要么收到事件、要么已取消订阅
+	sent := 0
+	for len(cases) > 0 {
+		chosen, _, _ := reflect.Select(cases)
+		if chosen%2 == 0 {
+			sent++
+		}
+		pairStart := chosen - chosen%2
+		cases = append(cases[:pairStart], cases[pairStart+2:]...)
+	}
+	return sent
+}
@@ -0,0 +1,57 @@
+package gopher_notify
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// 测试Feed-订阅与同步发送
+func TestFeed_SubscribeAndSend(t *testing.T) {
+	// 1.创建事件分发器
+	feed := NewFeed[string]()
+	// 2.创建接收通道并订阅
+	ch1, ch2 := make(chan string, 1), make(chan string, 1)
+	sub1 := feed.Subscribe(ch1)
+	feed.Subscribe(ch2)
+	// 3.启动协程消费事件
+	go func() {
+		for msg := range ch1 {
+			fmt.Printf("[接收者1]收到事件：%s\n", msg)
+		}
+	}()
+	go func() {
+		for msg := range ch2 {
+			fmt.Printf("[接收者2]收到事件：%s\n", msg)
+		}
+	}()
+	// 4.同步广播事件，返回值应当等于当前订阅者数量
+	count := feed.Send("aaa")
+	if count != 2 {
+		t.Fatalf("期望投递到2个订阅者，实际为%d", count)
+	}
+	time.Sleep(500 * time.Millisecond)
+	// 5.取消其中一个订阅后再次广播
+	sub1.Unsubscribe()
+	count = feed.Send("bbb")
+	if count != 1 {
+		t.Fatalf("期望投递到1个订阅者，实际为%d", count)
+	}
+	time.Sleep(500 * time.Millisecond)
+}
+
+// 测试Feed-取消订阅后Err通道被关闭
+func TestFeed_Unsubscribe(t *testing.T) {
+	// 1.创建事件分发器并订阅
+	feed := NewFeed[int]()
+	ch := make(chan int, 1)
+	sub := feed.Subscribe(ch)
+	// 2.取消订阅
+	sub.Unsubscribe()
+	// 3.Err通道应当被关闭
+	select {
+	case <-sub.Err():
+	case <-time.After(1 * time.Second):
+		t.Fatal("取消订阅后Err通道应当被关闭")
+	}
+}
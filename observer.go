@@ -21,6 +21,20 @@ type Observer[T any] interface {
 	OnUpdate(data T)
 }
 
+// RateMode 主题通知的限流模式，用于控制Notify被高频调用时，观察者实际被通知的时机
+type RateMode int
+
+const (
+	// DebounceLeading 防抖-前沿：数据变化时立即通知一次，随后冷却时间内的变化不会再次触发通知（会被丢弃）
+	DebounceLeading RateMode = iota
+	// DebounceTrailing 防抖-后沿：每次变化都会重新计时，仅在距离最后一次变化duration时间后，使用最新的数据通知一次
+	DebounceTrailing
+	// Throttle 节流：数据变化时立即通知一次，此后每隔duration最多通知一次，使用该时间段内最新挂起的数据
+	Throttle
+	// DebounceBoth 防抖-前后沿：数据变化时立即通知一次，并在冷却时间结束时，若期间仍有新的变化，再使用最新数据通知一次
+	DebounceBoth
+)
+
 // Subject 观察主题（被观察对象）
 //
 // 泛型：
@@ -30,11 +44,22 @@ type Subject[T any] struct {
 	data T
 	// 观察者该主题的观察者列表
 	observers sync.Map
-	// 防抖的时间间隔，设为0表示不使用防抖
-	debounceDuration time.Duration
-	// 是否正在防抖间隔冷却时间内
-	// 若为true，则主题变化且Notify调用时，也不会通知观察者
-	debounceFlag bool
+	// 通知的限流模式
+	rateMode RateMode
+	// 防抖/节流的时间间隔，设为0表示不限流
+	rateDuration time.Duration
+	// 是否正处于冷却窗口内
+	cooling bool
+	// 冷却窗口内挂起、尚未通知的最新数据
+	pending T
+	// 是否存在挂起的待通知数据
+	hasPending bool
+	// 挂起通知所使用的异步标志，取自最近一次限流模式下的Notify调用
+	lastAsync bool
+	// 当前复用的计时器，冷却窗口期间对其调用Reset而非重新创建
+	timer *time.Timer
+	// 用于结束计时器处理协程的信号通道
+	done chan struct{}
 	// 互斥锁，保证数据和定时器的安全操作
 	lock sync.Mutex
 }
@@ -42,44 +67,108 @@ type Subject[T any] struct {
 // NewSubject 创建一个被观察主题
 func NewSubject[T any]() *Subject[T] {
 	return &Subject[T]{
-		observers:        sync.Map{},
-		debounceDuration: 0,
-		debounceFlag:     false,
-		lock:             sync.Mutex{},
+		observers: sync.Map{},
+		lock:      sync.Mutex{},
 	}
 }
 
-// NewSubjectWithDebounce 创建一个被观察主题，带有防抖机制
+// NewSubjectWithRateLimit 创建一个被观察主题，并为其通知指定限流模式
 //
-//   - duration 防抖间隔，0表示不使用防抖
-//     若主题高频变化，就可能导致观察者被高频调用，出现资源浪费，可设定一个防抖间隔，在防抖时间间隔内出现的变化不会通知给观察者
-//     例如设为 1*time.Second 观察者会在防抖时间1秒后收到通知，即使在1秒内主题多次更新状态
-func NewSubjectWithDebounce[T any](duration time.Duration) *Subject[T] {
+//   - mode 限流模式，参见RateMode
+//   - duration 防抖/节流的时间间隔，0表示不限流
+//     若主题高频变化，就可能导致观察者被高频调用，出现资源浪费，可按需选择限流模式并设定时间间隔
+func NewSubjectWithRateLimit[T any](mode RateMode, duration time.Duration) *Subject[T] {
 	return &Subject[T]{
-		observers:        sync.Map{},
-		debounceDuration: duration,
-		debounceFlag:     false,
-		lock:             sync.Mutex{},
+		observers:    sync.Map{},
+		rateMode:     mode,
+		rateDuration: duration,
+		lock:         sync.Mutex{},
 	}
 }
 
 // 通知全部观察者的逻辑
 //
+//   - data 通知观察者时传递的数据
 //   - async 是否异步通知
-func (subject *Subject[T]) notifyObserver(async bool) {
+func (subject *Subject[T]) notifyObserver(data T, async bool) {
 	if async {
 		subject.observers.Range(func(key, value any) bool {
-			go key.(Observer[T]).OnUpdate(subject.data)
+			go key.(Observer[T]).OnUpdate(data)
 			return true
 		})
 	} else {
 		subject.observers.Range(func(key, value any) bool {
-			key.(Observer[T]).OnUpdate(subject.data)
+			key.(Observer[T]).OnUpdate(data)
 			return true
 		})
 	}
 }
 
+// 确保限流计时器及其处理协程已经启动，调用方需持有subject.lock
+func (subject *Subject[T]) ensureTimer() {
+	if subject.timer != nil {
+		return
+	}
+	// 计时器创建后立即停止，等待第一次Notify时才真正启动倒计时
+	subject.timer = time.NewTimer(subject.rateDuration)
+	if !subject.timer.Stop() {
+		<-subject.timer.C
+	}
+	subject.done = make(chan struct{})
+	go subject.timerLoop(subject.timer, subject.done)
+}
+
+// 计时器处理协程，计时器到期后结算冷却窗口并按需补发挂起的通知
+//
+//   - timer 本次协程关联的计时器
+//   - done 结束该协程的信号通道
+func (subject *Subject[T]) timerLoop(timer *time.Timer, done chan struct{}) {
+	for {
+		select {
+		case <-timer.C:
+			subject.lock.Lock()
+			mode := subject.rateMode
+			hadPending := subject.hasPending
+			data := subject.pending
+			async := subject.lastAsync
+			subject.hasPending = false
+			// 节流模式补发挂起数据后重新进入冷却窗口，保证每个duration内最多通知一次
+			if mode == Throttle && hadPending && subject.timer == timer {
+				timer.Reset(subject.rateDuration)
+			} else {
+				subject.cooling = false
+			}
+			subject.lock.Unlock()
+			// DebounceLeading没有后沿通知，其余模式若存在挂起的数据则补发
+			if mode != DebounceLeading && hadPending {
+				subject.notifyObserver(data, async)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// Stop 停止限流计时器，释放相关的协程资源，并丢弃尚未触发的挂起通知
+// 对未设置限流模式的Subject调用是安全的空操作；停止后再次调用Notify会重新启动计时器
+func (subject *Subject[T]) Stop() {
+	subject.lock.Lock()
+	defer subject.lock.Unlock()
+	if subject.timer == nil {
+		return
+	}
+	subject.timer.Stop()
+	close(subject.done)
+	subject.timer = nil
+	subject.cooling = false
+	subject.hasPending = false
+}
+
+// Close Stop的别名，用于满足资源释放场景下的惯用命名
+func (subject *Subject[T]) Close() {
+	subject.Stop()
+}
+
 // Register 注册观察者
 //
 //   - observers 要注册的观察者，不定长参数
@@ -106,26 +195,57 @@ func (subject *Subject[T]) Update(data T) {
 	subject.data = data
 }
 
-// Notify 将当前主题的数据传递并通知全部观察者
+// Notify 将当前主题的数据传递并通知全部观察者，具体的通知时机由限流模式（RateMode）决定
 //
 //   - async 是否异步通知
 func (subject *Subject[T]) Notify(async bool) {
 	// 上锁
 	subject.lock.Lock()
 	defer subject.lock.Unlock()
-	// 若处于防抖冷却时间内，则不进行通知
-	if subject.debounceFlag {
+	data := subject.data
+	// 未设置限流时间间隔，直接通知
+	if subject.rateDuration <= 0 {
+		subject.notifyObserver(data, async)
 		return
 	}
-	// 否则，执行通知
-	subject.notifyObserver(async)
-	// 设定防抖冷却，进入防抖状态
-	if subject.debounceDuration > 0 {
-		subject.debounceFlag = true
-		go func() {
-			time.Sleep(subject.debounceDuration)
-			subject.debounceFlag = false
-		}()
+	subject.ensureTimer()
+	subject.lastAsync = async
+	switch subject.rateMode {
+	case DebounceLeading:
+		// 冷却窗口内不通知，窗口外立即通知并重新进入冷却
+		if subject.cooling {
+			return
+		}
+		subject.cooling = true
+		subject.timer.Reset(subject.rateDuration)
+		subject.notifyObserver(data, async)
+	case DebounceTrailing:
+		// 每次调用都重置计时器，仅在最后一次变化duration时间后通知
+		subject.pending = data
+		subject.hasPending = true
+		subject.cooling = true
+		subject.timer.Reset(subject.rateDuration)
+	case Throttle:
+		// 挂起最新数据；冷却窗口外立即通知，窗口内的挂起数据由计时器到期时补发
+		subject.pending = data
+		subject.hasPending = true
+		if subject.cooling {
+			return
+		}
+		subject.cooling = true
+		subject.hasPending = false
+		subject.timer.Reset(subject.rateDuration)
+		subject.notifyObserver(data, async)
+	case DebounceBoth:
+		// 每次调用都重置计时器；冷却窗口外立即通知一次，窗口内仅挂起数据，等待计时器到期时补发
+		subject.pending = data
+		subject.hasPending = true
+		subject.timer.Reset(subject.rateDuration)
+		if !subject.cooling {
+			subject.cooling = true
+			subject.hasPending = false
+			subject.notifyObserver(data, async)
+		}
 	}
 }
 
@@ -20,7 +20,7 @@ func (observer *TextObserver) OnUpdate(data string) {
 // 测试观察者-更新并通知
 func TestObserver_UpdateAndNotify(t *testing.T) {
 	// 1.创建主题
-	subject := NewSubject[string](0)
+	subject := NewSubject[string]()
 	// 2.创建观察者实例
 	o1, o2 := &TextObserver{"观察者1"}, &TextObserver{"观察者2"}
 	// 3.订阅主题
@@ -34,7 +34,7 @@ func TestObserver_UpdateAndNotify(t *testing.T) {
 // 测试观察者-手动通知
 func TestObserver_ManuallyNotify(t *testing.T) {
 	// 1.创建主题
-	subject := NewSubject[string](0)
+	subject := NewSubject[string]()
 	// 2.创建观察者实例
 	o1, o2 := &TextObserver{"观察者1"}, &TextObserver{"观察者2"}
 	// 3.订阅主题
@@ -50,7 +50,7 @@ func TestObserver_ManuallyNotify(t *testing.T) {
 // 测试观察者-更新并异步通知
 func TestObserver_UpdateAndNotifyAsync(t *testing.T) {
 	// 1.创建主题
-	subject := NewSubject[string](0)
+	subject := NewSubject[string]()
 	// 2.创建观察者实例
 	o1, o2 := &TextObserver{"观察者1"}, &TextObserver{"观察者2"}
 	// 3.订阅主题
@@ -61,10 +61,10 @@ func TestObserver_UpdateAndNotifyAsync(t *testing.T) {
 	time.Sleep(1 * time.Second)
 }
 
-// 测试观察者-防抖
+// 测试观察者-防抖（前沿模式）
 func TestObserver_Debounce(t *testing.T) {
-	// 1.创建主题，设定防抖时间
-	subject := NewSubject[string](1 * time.Second)
+	// 1.创建主题，设定防抖-前沿模式及防抖时间
+	subject := NewSubjectWithRateLimit[string](DebounceLeading, 1*time.Second)
 	// 2.创建观察者实例
 	o1, o2 := &TextObserver{"观察者1"}, &TextObserver{"观察者2"}
 	// 3.订阅主题
@@ -75,4 +75,39 @@ func TestObserver_Debounce(t *testing.T) {
 		time.Sleep(200 * time.Millisecond)
 	}
 	time.Sleep(5 * time.Second)
+	subject.Close()
+}
+
+// 测试观察者-防抖（后沿模式），高频变化结束后使用最后一次数据通知一次
+func TestObserver_DebounceTrailing(t *testing.T) {
+	// 1.创建主题，设定防抖-后沿模式及防抖时间
+	subject := NewSubjectWithRateLimit[string](DebounceTrailing, 1*time.Second)
+	// 2.创建观察者实例
+	o1 := &TextObserver{"观察者1"}
+	// 3.订阅主题
+	subject.Register(o1)
+	// 4.连续变化，最终应仅收到最后一次数据"5"的通知
+	for i := 1; i <= 5; i++ {
+		subject.UpdateAndNotify(fmt.Sprintf("%d", i), false)
+		time.Sleep(200 * time.Millisecond)
+	}
+	time.Sleep(2 * time.Second)
+	subject.Close()
+}
+
+// 测试观察者-节流，高频变化期间每隔固定时间通知一次
+func TestObserver_Throttle(t *testing.T) {
+	// 1.创建主题，设定节流模式及间隔
+	subject := NewSubjectWithRateLimit[string](Throttle, 1*time.Second)
+	// 2.创建观察者实例
+	o1 := &TextObserver{"观察者1"}
+	// 3.订阅主题
+	subject.Register(o1)
+	// 4.连续变化，应当按固定间隔通知，且期间挂起的最新数据不会丢失
+	for i := 1; i <= 10; i++ {
+		subject.UpdateAndNotify(fmt.Sprintf("%d", i), false)
+		time.Sleep(200 * time.Millisecond)
+	}
+	time.Sleep(2 * time.Second)
+	subject.Close()
 }
\ No newline at end of file
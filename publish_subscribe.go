@@ -1,6 +1,10 @@
 package gopher_notify
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // 发布-订阅模式实现
 
@@ -61,6 +65,65 @@ type Subscriber[T comparable, D any] interface {
 	OnSubscribe(e *Event[T, D])
 }
 
+// predicateSubscriber 谓词订阅者条目
+// 保存一个自定义过滤函数以及关注该过滤条件的订阅者，不与固定主题绑定
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+type predicateSubscriber[T comparable, D any] struct {
+	// 过滤函数，返回true表示事件应当投递给该订阅者
+	filter func(e *Event[T, D]) bool
+	// 订阅者
+	subscriber Subscriber[T, D]
+}
+
+// DropPolicy 慢订阅者处理策略
+// 当向某个订阅者投递事件超过Broker设定的发布超时时间后，依据该策略决定如何处理该订阅者及其未投递的事件
+type DropPolicy int
+
+const (
+	// DropNewest 丢弃本次要投递的新事件，保留该订阅者队列中已缓冲的事件，订阅关系不受影响
+	DropNewest DropPolicy = iota
+	// DropOldest 丢弃该订阅者队列中最旧的一个事件，为新事件腾出空间
+	DropOldest
+	// EvictSubscriber 判定该订阅者为慢订阅者，直接将其取消订阅并关闭其投递队列
+	EvictSubscriber
+)
+
+// subscriberEntry 订阅者条目
+// 每个订阅者在订阅主题时都会拥有一个专属的缓冲投递队列以及一个专属的处理协程，避免单个慢订阅者阻塞其它订阅者的接收
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+type subscriberEntry[T comparable, D any] struct {
+	// 订阅者
+	subscriber Subscriber[T, D]
+	// 该订阅者专属的投递队列
+	channel chan *Event[T, D]
+	// 该订阅者累计被丢弃的事件数量
+	dropped atomic.Int64
+	// 保护channel发送与关闭之间互斥的读写锁：发送方（deliver/deliverSlow）各自持有读锁即可并发发送，互不阻塞；
+	// 关闭操作持有写锁，会等待全部正在进行的发送结束后才真正关闭channel，从而保证关闭与发送互斥，不会panic
+	sendLock sync.RWMutex
+	// 该订阅者是否已被取消订阅/移除，为true后不再允许向channel发送事件
+	closed bool
+	// 该订阅者当前是否已有一个deliverSlow协程在等待空位或超时，用于避免同一慢订阅者被无限堆积的等待协程拖垮
+	slowInFlight atomic.Bool
+}
+
+// 关闭该订阅者的投递队列，与deliver/deliverSlow的发送操作互斥，可安全地重复调用
+func (entry *subscriberEntry[T, D]) close() {
+	entry.sendLock.Lock()
+	defer entry.sendLock.Unlock()
+	if entry.closed {
+		return
+	}
+	entry.closed = true
+	close(entry.channel)
+}
+
 // Broker 事件总线
 //
 // 泛型：
@@ -69,10 +132,29 @@ type Subscriber[T comparable, D any] interface {
 type Broker[T comparable, D any] struct {
 	// 全部订阅者列表，其中：
 	//  - 键：订阅的主题，类型：T
-	//  - 值：对应主题的全部订阅者集合，类型：*sync.Map 键： Subscriber 值： void
+	//  - 值：对应主题的全部订阅者集合，类型：*sync.Map 键： Subscriber 值： *subscriberEntry
 	subscribers sync.Map
+	// 谓词订阅者列表，不依赖固定主题，而是通过自定义过滤函数决定事件是否投递
+	// 用于实现跨主题的订阅者，例如日志记录、审计等场景
+	predicateSubscribers []*predicateSubscriber[T, D]
+	// 保护谓词订阅者列表的互斥锁
+	predicateLock sync.Mutex
 	// 消息队列
 	queue chan *Event[T, D]
+	// 每个订阅者专属投递队列的缓冲区大小
+	subscriberBuffer int
+	// 单个订阅者的投递超时时间，超过该时间仍未投递成功则按dropPolicy处理，0表示不设置超时，一直阻塞等待
+	// 无论是否设置超时，某订阅者已有一个事件在等待空位时，其后到达且队列已满的事件都会被直接丢弃，参见deliver
+	publishTimeout time.Duration
+	// 投递超时后的处理策略
+	dropPolicy DropPolicy
+	// 全部订阅者累计被丢弃的事件总数
+	droppedCount atomic.Int64
+	// 事件回放日志存储，为nil表示未启用回放功能，参见NewBrokerWithReplay
+	logStore LogStore[T, D]
+	// 保护主题订阅者集合变更与广播投递之间一致性的互斥锁
+	// 主要用于协调SubscribeFromOffset的历史回放与broadcast的实时投递之间的边界，避免事件缺失或重复
+	topicLock sync.Mutex
 }
 
 // NewBroker 事件总线的构造函数
@@ -82,13 +164,19 @@ type Broker[T comparable, D any] struct {
 //   - D 处理的事件包含的内容类型
 //
 // 参数：
-//   - buffer 消息队列通道缓冲区大小
+//   - buffer 消息队列通道缓冲区大小，同时也是每个订阅者专属投递队列的缓冲区大小
 //     在订阅者处理消息逻辑耗时的情况下，可能导致发布操作被阻塞，可设定一定大小的通道缓冲区
-func NewBroker[T comparable, D any](buffer int) *Broker[T, D] {
+//   - publishTimeout 单个订阅者的投递超时时间，0表示不设置超时，一直阻塞等待该订阅者消费
+//     每个订阅者同一时刻只有一个事件处于等待状态，期间队列已满时到达的其它事件会被直接丢弃
+//   - dropPolicy 投递超时后对慢订阅者的处理策略
+func NewBroker[T comparable, D any](buffer int, publishTimeout time.Duration, dropPolicy DropPolicy) *Broker[T, D] {
 	// 创建一个Broker
 	broker := &Broker[T, D]{
-		subscribers: sync.Map{},
-		queue:       make(chan *Event[T, D], buffer),
+		subscribers:      sync.Map{},
+		queue:            make(chan *Event[T, D], buffer),
+		subscriberBuffer: buffer,
+		publishTimeout:   publishTimeout,
+		dropPolicy:       dropPolicy,
 	}
 	// 在一个新的线程中准备接收事件并广播
 	go func() {
@@ -103,40 +191,183 @@ func NewBroker[T comparable, D any](buffer int) *Broker[T, D] {
 //
 //   - event 发布的事件对象
 func (broker *Broker[T, D]) broadcast(event *Event[T, D]) {
-	// 获取主题对应的订阅者列表
-	topicMap, ok := broker.subscribers.Load(event.GetTopic())
-	if !ok {
-		return
+	// 追加到回放日志、获取主题对应的订阅者列表、以及投递到每个订阅者专属的队列，三者需在同一临界区内完成
+	// 从而与SubscribeFromOffset捕获回放边界的操作互斥，保证历史回放与实时投递之间不会产生缺失或重复
+	broker.topicLock.Lock()
+	if broker.logStore != nil {
+		broker.logStore.Append(event.GetTopic(), event)
 	}
-	// 执行事件发布
-	if event.async {
-		topicMap.(*sync.Map).Range(func(key, value any) bool {
-			go key.(Subscriber[T, D]).OnSubscribe(event)
-			return true
-		})
-	} else {
-		topicMap.(*sync.Map).Range(func(key, value any) bool {
-			key.(Subscriber[T, D]).OnSubscribe(event)
+	topicMapRaw, ok := broker.subscribers.Load(event.GetTopic())
+	if ok {
+		topicMap := topicMapRaw.(*sync.Map)
+		topicMap.Range(func(key, value any) bool {
+			broker.deliver(topicMap, value.(*subscriberEntry[T, D]), event)
 			return true
 		})
 	}
+	broker.topicLock.Unlock()
+	// 再将事件投递给全部满足过滤条件的谓词订阅者
+	broker.predicateLock.Lock()
+	predicateList := broker.predicateSubscribers
+	broker.predicateLock.Unlock()
+	for _, entry := range predicateList {
+		if !entry.filter(event) {
+			continue
+		}
+		if event.async {
+			go entry.subscriber.OnSubscribe(event)
+		} else {
+			entry.subscriber.OnSubscribe(event)
+		}
+	}
+}
+
+// 将事件投递到单个订阅者的专属队列中
+// 仅在缓冲区已有空位时立即发送，非阻塞地尝试一次，因此不会拖慢调用方（共享的派发协程）
+// 若缓冲区已满：当该订阅者尚无正在等待的慢投递协程时，交由deliverSlow在独立协程中等待空位/应用丢弃策略；
+// 若已有一个慢投递协程在等待（即该订阅者持续跟不上），本次事件直接按丢弃处理，不再额外堆积协程，避免无界的协程增长
+//
+//   - topicMap 该订阅者所在主题的订阅者集合，EvictSubscriber策略下需要据此取消订阅
+//   - entry 目标订阅者条目
+//   - event 要投递的事件对象
+func (broker *Broker[T, D]) deliver(topicMap *sync.Map, entry *subscriberEntry[T, D], event *Event[T, D]) {
+	entry.sendLock.RLock()
+	if entry.closed {
+		entry.sendLock.RUnlock()
+		return
+	}
+	select {
+	case entry.channel <- event:
+		entry.sendLock.RUnlock()
+		return
+	default:
+	}
+	entry.sendLock.RUnlock()
+	// 缓冲区已满：该订阅者已有一个慢投递协程在等待时，不再为其新增协程，直接丢弃本次事件
+	if !entry.slowInFlight.CompareAndSwap(false, true) {
+		entry.dropped.Add(1)
+		broker.droppedCount.Add(1)
+		return
+	}
+	go broker.deliverSlow(topicMap, entry, event)
+}
+
+// 在独立协程中处理因缓冲区已满而无法立即投递的事件
+// 超过publishTimeout仍未投递成功则按dropPolicy处理该慢订阅者；publishTimeout<=0时则一直等待，但只阻塞该订阅者自己的协程
+// 发送期间仅持有entry.sendLock的读锁，与deliver的非阻塞发送互不阻塞（channel本身保证并发发送安全）；
+// 只有close()持有写锁，会等待本次发送真正结束后才关闭channel，从而避免send-on-closed-channel的同时，也不会让deliver被慢投递拖慢
+// 注意：同一订阅者的多个事件一旦先后触发此慢路径，彼此之间不再严格保证投递顺序，这是避免阻塞共享派发协程所做的权衡
+//
+//   - topicMap 该订阅者所在主题的订阅者集合，EvictSubscriber策略下需要据此取消订阅
+//   - entry 目标订阅者条目
+//   - event 要投递的事件对象
+func (broker *Broker[T, D]) deliverSlow(topicMap *sync.Map, entry *subscriberEntry[T, D], event *Event[T, D]) {
+	defer entry.slowInFlight.Store(false)
+	entry.sendLock.RLock()
+	if entry.closed {
+		entry.sendLock.RUnlock()
+		return
+	}
+	// 未设置超时，一直等待直至投递成功，期间只持有读锁，不影响其它订阅者的投递
+	if broker.publishTimeout <= 0 {
+		entry.channel <- event
+		entry.sendLock.RUnlock()
+		return
+	}
+	// 尝试在超时时间内完成投递
+	select {
+	case entry.channel <- event:
+		entry.sendLock.RUnlock()
+		return
+	case <-time.After(broker.publishTimeout):
+	}
+	entry.sendLock.RUnlock()
+	// 投递超时，依据策略处理该慢订阅者
+	switch broker.dropPolicy {
+	case DropOldest:
+		// 丢弃队列中最旧的事件，为新事件腾出空间
+		entry.sendLock.RLock()
+		if !entry.closed {
+			select {
+			case <-entry.channel:
+				entry.dropped.Add(1)
+				broker.droppedCount.Add(1)
+			default:
+			}
+			select {
+			case entry.channel <- event:
+			default:
+				entry.dropped.Add(1)
+				broker.droppedCount.Add(1)
+			}
+		}
+		entry.sendLock.RUnlock()
+	case EvictSubscriber:
+		// 判定为慢订阅者，取消其订阅并关闭队列
+		topicMap.Delete(entry.subscriber)
+		entry.close()
+		entry.dropped.Add(1)
+		broker.droppedCount.Add(1)
+	default:
+		// DropNewest，丢弃本次要投递的新事件
+		entry.dropped.Add(1)
+		broker.droppedCount.Add(1)
+	}
+}
+
+// 从订阅者专属队列中取出事件并交由订阅者处理，每个订阅者在订阅时都会启动一个该协程
+//
+//   - entry 目标订阅者条目
+func (broker *Broker[T, D]) consume(entry *subscriberEntry[T, D]) {
+	for event := range entry.channel {
+		entry.subscriber.OnSubscribe(event)
+	}
 }
 
 // Subscribe 订阅一个主题
+// 每个订阅者会拥有一个专属的缓冲投递队列及处理协程，单个订阅者处理缓慢不会阻塞其它订阅者
 //
 //   - topic 要订阅的主题，不存在会创建
 //   - subscribers 订阅该主题的订阅者，不定长参数
 func (broker *Broker[T, D]) Subscribe(topic T, subscribers ...Subscriber[T, D]) {
 	// 主题不存在则创建
-	topicMap, ok := broker.subscribers.Load(topic)
+	topicMapRaw, ok := broker.subscribers.Load(topic)
 	if !ok {
-		topicMap = &sync.Map{}
-		broker.subscribers.Store(topic, topicMap)
+		topicMapRaw = &sync.Map{}
+		broker.subscribers.Store(topic, topicMapRaw)
 	}
-	// 加入主题列表
-	topicList := topicMap.(*sync.Map)
+	// 加入主题列表，为每个订阅者创建专属的投递队列并启动处理协程
+	topicMap := topicMapRaw.(*sync.Map)
 	for _, subscriber := range subscribers {
-		topicList.Store(subscriber, void{})
+		entry := &subscriberEntry[T, D]{
+			subscriber: subscriber,
+			channel:    make(chan *Event[T, D], broker.subscriberBuffer),
+		}
+		actual, loaded := topicMap.LoadOrStore(subscriber, entry)
+		// 该订阅者已订阅过此主题，沿用原有队列与协程，避免重复订阅造成协程泄漏
+		if loaded {
+			continue
+		}
+		go broker.consume(actual.(*subscriberEntry[T, D]))
+	}
+}
+
+// SubscribeFunc 通过自定义过滤函数订阅事件，不依赖固定主题
+// 每当有新事件发布时，会将事件传入filter进行判断，只有返回true时才会投递给subscribers
+// 若需要实现类似“全部主题”的订阅效果，可将filter固定返回true
+// 注意：与按sync.Map去重的Subscribe不同，本方法每次调用都会无条件向谓词订阅者列表追加一条新条目，不是幂等的；
+// 对同一个订阅者重复调用（哪怕filter相同）会导致该订阅者对每个匹配的事件收到多次投递，如需避免重复请由调用方自行保证不重复订阅
+//
+//   - filter 过滤函数，判断事件是否应当投递给该订阅者
+//   - subscribers 关注该过滤条件的订阅者，不定长参数
+func (broker *Broker[T, D]) SubscribeFunc(filter func(e *Event[T, D]) bool, subscribers ...Subscriber[T, D]) {
+	broker.predicateLock.Lock()
+	defer broker.predicateLock.Unlock()
+	for _, subscriber := range subscribers {
+		broker.predicateSubscribers = append(broker.predicateSubscribers, &predicateSubscriber[T, D]{
+			filter:     filter,
+			subscriber: subscriber,
+		})
 	}
 }
 
@@ -145,31 +376,97 @@ func (broker *Broker[T, D]) Subscribe(topic T, subscribers ...Subscriber[T, D])
 //   - topic 要取消订阅的主题，不存在则不会做任何操作
 //   - subscriber 订阅该主题的订阅者
 func (broker *Broker[T, D]) UnSubscribe(topic T, subscriber Subscriber[T, D]) {
-	// 移出订阅者列表
+	// 移出订阅者列表，并关闭其投递队列，结束对应的处理协程
 	topicMap, ok := broker.subscribers.Load(topic)
+	if !ok {
+		return
+	}
+	entry, ok := topicMap.(*sync.Map).LoadAndDelete(subscriber)
 	if ok {
-		topicMap.(*sync.Map).Delete(subscriber)
+		entry.(*subscriberEntry[T, D]).close()
+	}
+}
+
+// UnsubscribeFunc 取消通过SubscribeFunc建立的谓词订阅，会移除该订阅者全部已注册的过滤订阅条目
+//
+//   - subscriber 要取消订阅的订阅者
+func (broker *Broker[T, D]) UnsubscribeFunc(subscriber Subscriber[T, D]) {
+	broker.predicateLock.Lock()
+	defer broker.predicateLock.Unlock()
+	// 新建切片而非原地复用底层数组，避免改写broadcast在锁外正在遍历的列表副本
+	remaining := make([]*predicateSubscriber[T, D], 0, len(broker.predicateSubscribers))
+	for _, entry := range broker.predicateSubscribers {
+		if entry.subscriber == subscriber {
+			continue
+		}
+		remaining = append(remaining, entry)
 	}
+	broker.predicateSubscribers = remaining
 }
 
 // RemoveTopic 移除某个主题，该主题全部的订阅者也会被全部取消订阅
 //
 //   - topic 要移除的主题
 func (broker *Broker[T, D]) RemoveTopic(topic T) {
-	topicMap, ok := broker.subscribers.Load(topic)
+	topicMapRaw, ok := broker.subscribers.Load(topic)
 	if ok {
-		topicMap.(*sync.Map).Clear()
+		topicMap := topicMapRaw.(*sync.Map)
+		topicMap.Range(func(key, value any) bool {
+			value.(*subscriberEntry[T, D]).close()
+			return true
+		})
+		topicMap.Clear()
 		broker.subscribers.Delete(topic)
 	}
 }
 
-// RemoveAll 移除全部主题及其订阅者
+// RemoveAll 移除全部主题及其订阅者，以及全部谓词订阅者
 func (broker *Broker[T, D]) RemoveAll() {
 	broker.subscribers.Range(func(key, value any) bool {
-		value.(*sync.Map).Clear()
+		topicMap := value.(*sync.Map)
+		topicMap.Range(func(_, v any) bool {
+			v.(*subscriberEntry[T, D]).close()
+			return true
+		})
+		topicMap.Clear()
 		return true
 	})
 	broker.subscribers.Clear()
+	broker.predicateLock.Lock()
+	broker.predicateSubscribers = nil
+	broker.predicateLock.Unlock()
+}
+
+// BrokerStats Broker运行状态统计信息，由Broker.Stats方法返回
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+type BrokerStats[T comparable] struct {
+	// 按主题统计的订阅者数量，键为主题，值为该主题下的订阅者个数
+	TopicSubscribers map[T]int
+	// 累计被丢弃的事件总数
+	DroppedEvents int64
+	// 当前消息队列中积压的事件数量
+	QueueDepth int
+}
+
+// Stats 获取当前Broker的运行状态，包括各主题的订阅者数量、累计被丢弃的事件数以及消息队列积压深度
+func (broker *Broker[T, D]) Stats() BrokerStats[T] {
+	stats := BrokerStats[T]{
+		TopicSubscribers: make(map[T]int),
+		DroppedEvents:    broker.droppedCount.Load(),
+		QueueDepth:       len(broker.queue),
+	}
+	broker.subscribers.Range(func(key, value any) bool {
+		count := 0
+		value.(*sync.Map).Range(func(_, _ any) bool {
+			count++
+			return true
+		})
+		stats.TopicSubscribers[key.(T)] = count
+		return true
+	})
+	return stats
 }
 
 // Close 关闭Broker的消息队列，释放资源，关闭后该Broker无法再被用于发布消息
@@ -2,6 +2,7 @@ package gopher_notify
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -21,7 +22,7 @@ func (subscriber *MessageSubscriber) OnSubscribe(e *Event[string, string]) {
 // 测试发布-订阅功能
 func TestPublish_Subscribe(t *testing.T) {
 	// 1.创建事件总线
-	broker := NewBroker[string, string](3)
+	broker := NewBroker[string, string](3, 0, DropNewest)
 	// 2.创建发布者
 	publisher := NewBasePublisher[string, string](broker)
 	// 3.创建订阅者
@@ -39,4 +40,52 @@ func TestPublish_Subscribe(t *testing.T) {
 	// 测试宕机捕获
 	publisher.Publish(NewEvent(topicOne, "aaa"), false)
 	time.Sleep(1 * time.Second)
+}
+
+// 测试发布-订阅-基于过滤函数的订阅
+func TestPublish_SubscribeFunc(t *testing.T) {
+	// 1.创建事件总线
+	broker := NewBroker[string, string](3, 0, DropNewest)
+	// 2.创建发布者
+	publisher := NewBasePublisher[string, string](broker)
+	// 3.创建一个跨主题的订阅者，仅关注内容中带有关键字"important"的事件
+	auditor := &MessageSubscriber{"审计订阅者"}
+	broker.SubscribeFunc(func(e *Event[string, string]) bool {
+		return strings.Contains(e.GetData(), "important")
+	}, auditor)
+	// 4.发布者发布事件，不论主题是什么，只要内容满足过滤条件就会被投递
+	publisher.Publish(NewEvent("topic-1", "normal message"), false)
+	publisher.Publish(NewEvent("topic-2", "an important message"), false)
+	time.Sleep(3 * time.Second)
+	broker.Close()
+}
+
+// 慢订阅者，用于测试发布超时与丢弃策略
+type SlowSubscriber struct {
+	// 名字
+	name string
+}
+
+// 模拟处理缓慢，每条事件耗时较长
+func (subscriber *SlowSubscriber) OnSubscribe(e *Event[string, string]) {
+	time.Sleep(3 * time.Second)
+}
+
+// 测试发布-订阅-慢订阅者超时后按DropNewest策略丢弃事件
+func TestPublish_SlowSubscriberDrop(t *testing.T) {
+	// 1.创建事件总线，订阅者队列缓冲为0，且设置较短的发布超时时间
+	broker := NewBroker[string, string](0, 100*time.Millisecond, DropNewest)
+	// 2.创建发布者
+	publisher := NewBasePublisher[string, string](broker)
+	// 3.创建一个处理缓慢的订阅者
+	broker.Subscribe("topic-1", &SlowSubscriber{"慢订阅者"})
+	// 4.连续发布多个事件，由于订阅者处理缓慢，后续事件将被丢弃
+	for i := 0; i < 5; i++ {
+		publisher.Publish(NewEvent("topic-1", fmt.Sprintf("msg-%d", i)), false)
+	}
+	time.Sleep(500 * time.Millisecond)
+	// 5.查看运行状态，应当存在被丢弃的事件
+	stats := broker.Stats()
+	fmt.Printf("主题订阅者数量：%v，丢弃事件数：%d，队列积压：%d\n", stats.TopicSubscribers, stats.DroppedEvents, stats.QueueDepth)
+	broker.Close()
 }
\ No newline at end of file
@@ -0,0 +1,204 @@
+package gopher_notify
+
+import "sync"
+
+// 事件回放日志实现
+
+// LogStore 事件回放日志的存储接口
+// Broker默认通过NewBrokerWithReplay使用内置的内存环形缓冲区实现，用户也可以自行实现该接口，接入文件、数据库等持久化存储，并通过NewBrokerWithLogStore传入
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+type LogStore[T comparable, D any] interface {
+	// Append 向指定主题的日志追加一条事件，返回该事件被分配到的偏移量，偏移量按主题单调递增
+	//
+	//   - topic 事件所属的主题
+	//   - event 要追加的事件对象
+	Append(topic T, event *Event[T, D]) int64
+	// Range 获取指定主题中偏移量大于等于from的全部事件，按偏移量升序排列
+	//
+	//   - topic 查询的主题
+	//   - from 起始偏移量（包含）
+	Range(topic T, from int64) []*Event[T, D]
+}
+
+// logEntry 日志中的一条记录，保存事件本身及其被分配到的偏移量
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+type logEntry[T comparable, D any] struct {
+	// 偏移量
+	offset int64
+	// 事件对象
+	event *Event[T, D]
+}
+
+// topicLog 单个主题的有界内存环形缓冲区日志
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+type topicLog[T comparable, D any] struct {
+	// 保护下方字段的互斥锁
+	lock sync.Mutex
+	// 保留的历史事件，超出capacity后最旧的记录会被丢弃
+	entries []logEntry[T, D]
+	// 缓冲区容量，小于等于0表示不保留历史事件
+	capacity int
+	// 下一个要分配的偏移量
+	nextOffset int64
+}
+
+// newTopicLog 创建一个指定容量的主题日志
+//
+//   - capacity 缓冲区容量
+func newTopicLog[T comparable, D any](capacity int) *topicLog[T, D] {
+	return &topicLog[T, D]{
+		capacity: capacity,
+	}
+}
+
+// append 追加一条事件，返回分配到的偏移量
+func (log *topicLog[T, D]) append(event *Event[T, D]) int64 {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	offset := log.nextOffset
+	log.nextOffset++
+	if log.capacity <= 0 {
+		return offset
+	}
+	log.entries = append(log.entries, logEntry[T, D]{offset: offset, event: event})
+	if len(log.entries) > log.capacity {
+		log.entries = log.entries[1:]
+	}
+	return offset
+}
+
+// rangeFrom 获取偏移量大于等于from的全部历史事件
+func (log *topicLog[T, D]) rangeFrom(from int64) []*Event[T, D] {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	var result []*Event[T, D]
+	for _, entry := range log.entries {
+		if entry.offset < from {
+			continue
+		}
+		result = append(result, entry.event)
+	}
+	return result
+}
+
+// ringLogStore LogStore接口的默认内存实现，按主题各自维护一个有界的环形缓冲区
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+type ringLogStore[T comparable, D any] struct {
+	// 每个主题保留的历史事件条数上限
+	perTopicHistory int
+	// 全部主题的日志，键：主题，类型：T 值：*topicLog[T, D]
+	logs sync.Map
+}
+
+// newRingLogStore 创建一个内存环形缓冲区日志存储
+//
+//   - perTopicHistory 每个主题保留的历史事件条数上限
+func newRingLogStore[T comparable, D any](perTopicHistory int) *ringLogStore[T, D] {
+	return &ringLogStore[T, D]{
+		perTopicHistory: perTopicHistory,
+	}
+}
+
+// 获取（不存在则创建）指定主题对应的日志
+func (store *ringLogStore[T, D]) topicLogFor(topic T) *topicLog[T, D] {
+	logRaw, ok := store.logs.Load(topic)
+	if !ok {
+		logRaw, _ = store.logs.LoadOrStore(topic, newTopicLog[T, D](store.perTopicHistory))
+	}
+	return logRaw.(*topicLog[T, D])
+}
+
+// Append 实现LogStore接口
+func (store *ringLogStore[T, D]) Append(topic T, event *Event[T, D]) int64 {
+	return store.topicLogFor(topic).append(event)
+}
+
+// Range 实现LogStore接口
+func (store *ringLogStore[T, D]) Range(topic T, from int64) []*Event[T, D] {
+	return store.topicLogFor(topic).rangeFrom(from)
+}
+
+// NewBrokerWithReplay 创建一个带有事件回放能力的事件总线
+// 每个主题发布的事件都会被追加到一个有界的内存环形缓冲区中，并分配单调递增的偏移量
+// 配合SubscribeFromOffset，新加入的订阅者可以先补齐历史事件，再无缝衔接到实时事件流，类似Kafka的迟到者回放
+//
+// 泛型：
+//   - T 处理的事件的主题类型
+//   - D 处理的事件包含的内容类型
+//
+// 参数：
+//   - buffer 消息队列通道缓冲区大小，同时也是每个订阅者专属投递队列的缓冲区大小
+//   - perTopicHistory 每个主题保留的历史事件条数上限
+func NewBrokerWithReplay[T comparable, D any](buffer int, perTopicHistory int) *Broker[T, D] {
+	broker := NewBroker[T, D](buffer, 0, DropNewest)
+	broker.logStore = newRingLogStore[T, D](perTopicHistory)
+	return broker
+}
+
+// NewBrokerWithLogStore 创建一个使用指定日志存储实现事件回放的事件总线，其余行为与NewBrokerWithReplay相同
+//
+//   - buffer 消息队列通道缓冲区大小，同时也是每个订阅者专属投递队列的缓冲区大小
+//   - store 事件回放日志存储，为nil时不启用回放功能
+func NewBrokerWithLogStore[T comparable, D any](buffer int, store LogStore[T, D]) *Broker[T, D] {
+	broker := NewBroker[T, D](buffer, 0, DropNewest)
+	broker.logStore = store
+	return broker
+}
+
+// SubscribeFromOffset 从指定偏移量开始订阅一个主题，仅在Broker通过NewBrokerWithReplay创建、启用了回放功能时可用
+// 该方法会先在一个专属协程中，将日志中偏移量大于等于fromOffset的历史事件按序回放给订阅者，再将其接入实时事件流
+// 捕获历史事件与接入实时投递队列处于同一临界区内完成，因此回放的历史事件与此后到来的实时事件之间不会产生缺失或重复
+//
+//   - topic 要订阅的主题，不存在会创建
+//   - fromOffset 起始偏移量（包含），回放该偏移量之后全部仍在缓冲区中的历史事件
+//   - sub 订阅该主题的订阅者
+//
+// 返回是否实际执行了本次回放：若该订阅者此前已订阅过该主题，会沿用原有队列与协程、不重复回放，返回false；
+// 调用方若需要对一个已有订阅“补齐”历史事件，应当先UnSubscribe再重新调用本方法
+func (broker *Broker[T, D]) SubscribeFromOffset(topic T, fromOffset int64, sub Subscriber[T, D]) bool {
+	// 未启用回放功能，退化为普通订阅
+	if broker.logStore == nil {
+		broker.Subscribe(topic, sub)
+		return false
+	}
+	broker.topicLock.Lock()
+	// 主题不存在则创建
+	topicMapRaw, ok := broker.subscribers.Load(topic)
+	if !ok {
+		topicMapRaw = &sync.Map{}
+		broker.subscribers.Store(topic, topicMapRaw)
+	}
+	topicMap := topicMapRaw.(*sync.Map)
+	entry := &subscriberEntry[T, D]{
+		subscriber: sub,
+		channel:    make(chan *Event[T, D], broker.subscriberBuffer),
+	}
+	actual, loaded := topicMap.LoadOrStore(sub, entry)
+	attached := actual.(*subscriberEntry[T, D])
+	// 在与broadcast相同的临界区内捕获历史事件，确保二者之间的边界是一致的
+	history := broker.logStore.Range(topic, fromOffset)
+	broker.topicLock.Unlock()
+	// 该订阅者已订阅过此主题，沿用原有队列与协程，不再重复回放
+	if loaded {
+		return false
+	}
+	go func() {
+		for _, historyEvent := range history {
+			attached.subscriber.OnSubscribe(historyEvent)
+		}
+		broker.consume(attached)
+	}()
+	return true
+}
@@ -0,0 +1,51 @@
+package gopher_notify
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 记录型订阅者，记录收到的全部事件内容，用于校验回放结果
+type RecordingSubscriber struct {
+	// 名字
+	name string
+	// 保护received的互斥锁
+	lock sync.Mutex
+	// 收到的全部事件内容
+	received []string
+}
+
+// 记录收到的事件
+func (subscriber *RecordingSubscriber) OnSubscribe(e *Event[string, string]) {
+	subscriber.lock.Lock()
+	defer subscriber.lock.Unlock()
+	subscriber.received = append(subscriber.received, e.GetData())
+}
+
+// 测试发布-订阅-事件回放，迟到的订阅者可以补齐历史事件
+func TestPublish_SubscribeFromOffset(t *testing.T) {
+	// 1.创建带有回放能力的事件总线
+	broker := NewBrokerWithReplay[string, string](3, 10)
+	// 2.创建发布者，先发布若干条历史事件
+	publisher := NewBasePublisher[string, string](broker)
+	for i := 0; i < 3; i++ {
+		publisher.Publish(NewEvent("topic-1", fmt.Sprintf("history-%d", i)), false)
+	}
+	time.Sleep(500 * time.Millisecond)
+	// 3.迟到的订阅者从偏移量0开始订阅，应当先收到全部历史事件
+	recorder := &RecordingSubscriber{name: "迟到订阅者"}
+	broker.SubscribeFromOffset("topic-1", 0, recorder)
+	time.Sleep(500 * time.Millisecond)
+	// 4.随后发布的实时事件也应当被追加到接收结果中，不丢失也不重复
+	publisher.Publish(NewEvent("topic-1", "live-0"), false)
+	time.Sleep(500 * time.Millisecond)
+	recorder.lock.Lock()
+	fmt.Printf("[%s]收到的全部事件：%v\n", recorder.name, recorder.received)
+	if len(recorder.received) != 4 {
+		t.Fatalf("期望收到4条事件，实际收到%d条：%v", len(recorder.received), recorder.received)
+	}
+	recorder.lock.Unlock()
+	broker.Close()
+}